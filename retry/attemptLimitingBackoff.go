@@ -0,0 +1,49 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package retry
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AttemptLimitingBackoff wraps another Backoff and stops retrying once a
+// maximum number of attempts has been made.
+type AttemptLimitingBackoff struct {
+	inner       Backoff
+	maxAttempts int
+}
+
+// NewAttemptLimitingBackoff creates an AttemptLimitingBackoff that delegates
+// to inner for the first maxAttempts attempts, then reports no more attempts
+// should be made.
+func NewAttemptLimitingBackoff(inner Backoff, maxAttempts int) (*AttemptLimitingBackoff, error) {
+	if inner == nil {
+		return nil, errors.New("retry: inner backoff must not be nil")
+	}
+	if maxAttempts <= 0 {
+		return nil, fmt.Errorf("retry: maxAttempts must be > 0, got %d", maxAttempts)
+	}
+	return &AttemptLimitingBackoff{inner: inner, maxAttempts: maxAttempts}, nil
+}
+
+// NextDelayMillis delegates to the wrapped Backoff until attempt reaches
+// maxAttempts, after which it always returns -1.
+func (b *AttemptLimitingBackoff) NextDelayMillis(attempt int) int64 {
+	if attempt >= b.maxAttempts {
+		return -1
+	}
+	return b.inner.NextDelayMillis(attempt)
+}
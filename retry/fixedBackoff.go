@@ -0,0 +1,35 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package retry
+
+import "fmt"
+
+// FixedBackoff waits the same delay between every attempt.
+type FixedBackoff struct {
+	delayMillis int64
+}
+
+// NewFixedBackoff creates a FixedBackoff that waits delayMillis before every attempt.
+func NewFixedBackoff(delayMillis int64) (*FixedBackoff, error) {
+	if delayMillis < 0 {
+		return nil, fmt.Errorf("retry: delayMillis must be >= 0, got %d", delayMillis)
+	}
+	return &FixedBackoff{delayMillis: delayMillis}, nil
+}
+
+// NextDelayMillis always returns the configured delay.
+func (b *FixedBackoff) NextDelayMillis(attempt int) int64 {
+	return b.delayMillis
+}
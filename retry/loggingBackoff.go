@@ -0,0 +1,85 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LoggingBackoff wraps another Backoff and, once an attempt reaches logAfter,
+// invokes logger with the attempt number and the delay the wrapped Backoff
+// computed for it — analogous to the "log after N retries" option on pd's
+// Backoffer, so a long-lived retry loop becomes observable without
+// instrumenting every call site by hand.
+type LoggingBackoff struct {
+	inner    Backoff
+	logAfter int
+	logger   func(attempt int, delayMillis int64)
+
+	mu          sync.Mutex
+	minInterval time.Duration
+	lastLog     time.Time
+}
+
+// NewLoggingBackoff creates a LoggingBackoff wrapping inner.
+func NewLoggingBackoff(inner Backoff, logAfter int, logger func(attempt int, delayMillis int64)) (*LoggingBackoff, error) {
+	if inner == nil {
+		return nil, errors.New("retry: inner backoff must not be nil")
+	}
+	if logAfter < 0 {
+		return nil, fmt.Errorf("retry: logAfter must be >= 0, got %d", logAfter)
+	}
+	if logger == nil {
+		return nil, errors.New("retry: logger must not be nil")
+	}
+	return &LoggingBackoff{inner: inner, logAfter: logAfter, logger: logger}, nil
+}
+
+// WithMinLogInterval throttles logger to at most once per d, so a tight retry
+// loop past logAfter doesn't flood logs on transient failures. The zero value
+// (the default) logs on every qualifying attempt. Returns b for chaining.
+func (b *LoggingBackoff) WithMinLogInterval(d time.Duration) *LoggingBackoff {
+	b.mu.Lock()
+	b.minInterval = d
+	b.mu.Unlock()
+	return b
+}
+
+// NextDelayMillis delegates to the wrapped Backoff and, once attempt reaches
+// logAfter, reports the decision via logger.
+func (b *LoggingBackoff) NextDelayMillis(attempt int) int64 {
+	delayMillis := b.inner.NextDelayMillis(attempt)
+
+	if attempt >= b.logAfter && b.shouldLog() {
+		b.logger(attempt, delayMillis)
+	}
+
+	return delayMillis
+}
+
+func (b *LoggingBackoff) shouldLog() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if b.minInterval > 0 && !b.lastLog.IsZero() && now.Sub(b.lastLog) < b.minInterval {
+		return false
+	}
+	b.lastLog = now
+	return true
+}
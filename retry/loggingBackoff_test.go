@@ -0,0 +1,75 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoggingBackoff(t *testing.T) {
+	if _, err := NewLoggingBackoff(nil, 1, func(int, int64) {}); err == nil {
+		t.FailNow()
+	}
+
+	fixedBackoff, _ := NewFixedBackoff(42)
+	if _, err := NewLoggingBackoff(fixedBackoff, 1, nil); err == nil {
+		t.FailNow()
+	}
+
+	var logged []int
+	b, err := NewLoggingBackoff(fixedBackoff, 2, func(attempt int, delayMillis int64) {
+		if delayMillis != 42 {
+			t.Fatalf("expected delay 42, got %d", delayMillis)
+		}
+		logged = append(logged, attempt)
+	})
+	if err != nil || b == nil {
+		t.FailNow()
+	}
+
+	for i := 0; i < 4; i++ {
+		b.NextDelayMillis(i)
+	}
+	if want := []int{2, 3}; !equalInts(logged, want) {
+		t.Fatalf("expected %v, got %v", want, logged)
+	}
+}
+
+func TestLoggingBackoffMinInterval(t *testing.T) {
+	fixedBackoff, _ := NewFixedBackoff(1)
+	var calls int
+	b, _ := NewLoggingBackoff(fixedBackoff, 0, func(int, int64) { calls++ })
+	b.WithMinLogInterval(time.Hour)
+
+	for i := 0; i < 5; i++ {
+		b.NextDelayMillis(i)
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 log call while throttled, got %d", calls)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/JohnTitor/garr/retry"
+)
+
+// ExecuteWithBackoff runs exec on pool, retrying on error according to
+// backoff: after attempt n (0-indexed) fails it consults
+// backoff.NextDelayMillis(n+1), the delay before the next attempt;
+// if the result is >= 0 the retry sleeps that long (respecting pool's
+// context) and re-enqueues the next attempt onto pool, otherwise the final
+// error is published to the returned Task's Result().
+func ExecuteWithBackoff(pool *Pool, backoff retry.Backoff, exec func(ctx context.Context, attempt int) (interface{}, error)) *Task {
+	final := NewTask(pool.ctx, nil)
+
+	var run func(ctx context.Context, attempt int)
+	run = func(ctx context.Context, attempt int) {
+		var ran int32 // set once exec actually starts running
+
+		t := &Task{
+			ctx:    ctx,
+			future: make(chan *TaskResult, 1),
+			executor: func(ctx context.Context) (interface{}, error) {
+				atomic.StoreInt32(&ran, 1)
+
+				// exec is recovered here rather than left to the per-attempt
+				// Task's own recover: that would land the panic's TaskResult
+				// on this throwaway task instead of final, leaving
+				// final.Result() unresolved forever.
+				result, err := recoverExec(ctx, attempt, exec)
+				if err == nil {
+					final.future <- &TaskResult{Result: result}
+					return result, nil
+				}
+
+				delayMillis := backoff.NextDelayMillis(attempt + 1)
+				if delayMillis < 0 {
+					final.future <- &TaskResult{Err: err}
+					return nil, err
+				}
+
+				// sleep outside of the worker so the backoff delay doesn't
+				// tie up a pool slot, then re-enqueue the next attempt.
+				go func() {
+					timer := time.NewTimer(time.Duration(delayMillis) * time.Millisecond)
+					defer stopTimer(timer)
+
+					select {
+					case <-ctx.Done():
+						final.future <- &TaskResult{Err: ctx.Err()}
+					case <-timer.C:
+						run(ctx, attempt+1)
+					}
+				}()
+
+				return nil, err
+			},
+		}
+
+		pool.Do(t)
+
+		// Do enqueues t but doesn't report whether it was accepted: if the
+		// pool or ctx is already done, push rejects t by writing
+		// straight to its future without ever running the executor above, so
+		// final would otherwise never hear about it. Forward that rejection;
+		// if the executor did run, it already reported to final itself.
+		go func() {
+			res := <-t.Result()
+			if atomic.LoadInt32(&ran) == 0 {
+				final.future <- res
+			}
+		}()
+	}
+
+	run(pool.ctx, 0)
+	return final
+}
+
+// recoverExec runs exec and converts a panic into an error, the same way
+// Task.Execute does for an ordinary task, so a panicking exec is retried (or
+// surfaced on final) exactly like any other failure instead of being caught
+// by the per-attempt Task's own recover and stranding final.Result().
+func recoverExec(ctx context.Context, attempt int, exec func(ctx context.Context, attempt int) (interface{}, error)) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("workerpool: task panicked: %v", r)
+		}
+	}()
+	return exec(ctx, attempt)
+}
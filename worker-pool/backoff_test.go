@@ -0,0 +1,116 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JohnTitor/garr/retry"
+)
+
+func TestExecuteWithBackoffRetriesUntilSuccess(t *testing.T) {
+	p := NewPool(context.Background(), Option{NumberWorker: 2})
+	defer p.Stop()
+
+	backoff, _ := retry.NewFixedBackoff(5)
+
+	var attempts int32
+	task := ExecuteWithBackoff(p, backoff, func(ctx context.Context, attempt int) (interface{}, error) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return nil, errors.New("not yet")
+		}
+		return "done", nil
+	})
+
+	select {
+	case res := <-task.Result():
+		if res.Err != nil || res.Result != "done" {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+		if atomic.LoadInt32(&attempts) != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for retries to succeed")
+	}
+}
+
+func TestExecuteWithBackoffStopsAfterLimit(t *testing.T) {
+	p := NewPool(context.Background(), Option{NumberWorker: 2})
+	defer p.Stop()
+
+	fixed, _ := retry.NewFixedBackoff(5)
+	backoff, _ := retry.NewAttemptLimitingBackoff(fixed, 2)
+
+	wantErr := errors.New("always fails")
+	task := ExecuteWithBackoff(p, backoff, func(ctx context.Context, attempt int) (interface{}, error) {
+		return nil, wantErr
+	})
+
+	select {
+	case res := <-task.Result():
+		if !errors.Is(res.Err, wantErr) {
+			t.Fatalf("expected final error %v, got %v", wantErr, res.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for final error")
+	}
+}
+
+func TestExecuteWithBackoffRecoversPanic(t *testing.T) {
+	p := NewPool(context.Background(), Option{NumberWorker: 2})
+	defer p.Stop()
+
+	fixed, _ := retry.NewFixedBackoff(5)
+	backoff, _ := retry.NewAttemptLimitingBackoff(fixed, 2)
+
+	task := ExecuteWithBackoff(p, backoff, func(ctx context.Context, attempt int) (interface{}, error) {
+		panic("boom")
+	})
+
+	select {
+	case res := <-task.Result():
+		if res.Err == nil {
+			t.Fatal("expected the panic to surface as an error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("final never resolved after a panicking exec")
+	}
+}
+
+func TestExecuteWithBackoffOnStoppedPool(t *testing.T) {
+	p := NewPool(context.Background(), Option{NumberWorker: 2})
+	p.Stop()
+
+	fixed, _ := retry.NewFixedBackoff(5)
+	backoff, _ := retry.NewAttemptLimitingBackoff(fixed, 2)
+
+	task := ExecuteWithBackoff(p, backoff, func(ctx context.Context, attempt int) (interface{}, error) {
+		return "done", nil
+	})
+
+	select {
+	case res := <-task.Result():
+		if res.Err == nil {
+			t.Fatal("expected an error for a task rejected by a stopped pool")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("final never resolved after the per-attempt task was rejected")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import "time"
+
+// Observer receives lifecycle events from Pool. Implementations must be safe
+// for concurrent use; Pool invokes them synchronously from Do, TryDo, worker
+// and expandedWorker, so they should return quickly. A Pool with no Observer
+// configured skips these calls entirely.
+type Observer interface {
+	// OnEnqueue is called when a task is accepted onto the pool.
+	OnEnqueue()
+	// OnStart is called when a worker begins executing a task.
+	OnStart()
+	// OnFinish is called when a task finishes executing, successfully or not.
+	OnFinish(dur time.Duration, err error)
+	// OnReject is called when a task is rejected instead of accepted (the
+	// pool or the task's context was already done, or TryDo found the queue
+	// full).
+	OnReject()
+	// OnWorkerSpawn is called when a core or expanded worker goroutine starts.
+	OnWorkerSpawn()
+	// OnWorkerExit is called when a worker goroutine returns.
+	OnWorkerExit()
+}
+
+func (p *Pool) onEnqueue() {
+	if obs := p.opt.Observer; obs != nil {
+		obs.OnEnqueue()
+	}
+}
+
+func (p *Pool) onReject() {
+	if obs := p.opt.Observer; obs != nil {
+		obs.OnReject()
+	}
+}
+
+func (p *Pool) onWorkerSpawn() {
+	if obs := p.opt.Observer; obs != nil {
+		obs.OnWorkerSpawn()
+	}
+}
+
+func (p *Pool) onWorkerExit() {
+	if obs := p.opt.Observer; obs != nil {
+		obs.OnWorkerExit()
+	}
+}
+
+// runTask executes task, recording its duration in Pool's Stats and, if an
+// Observer is configured, reporting OnStart/OnFinish around it. With no
+// Observer this reduces to task.Execute() plus a duration measurement and
+// allocates nothing beyond the TaskResult that Execute itself creates.
+func (p *Pool) runTask(task *Task) {
+	obs := p.opt.Observer
+	if obs != nil {
+		obs.OnStart()
+	}
+
+	start := time.Now()
+	res := task.Execute()
+	dur := time.Since(start)
+
+	p.stats.recordFinish(dur)
+
+	if obs != nil {
+		var err error
+		if res != nil {
+			err = res.Err
+		}
+		obs.OnFinish(dur, err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"testing"
+)
+
+var benchNoopExecutor = func(ctx context.Context) (interface{}, error) { return nil, nil }
+
+// BenchmarkRunTaskNoObserver, compared against BenchmarkRunTaskWithObserver,
+// demonstrates that registering an Observer costs no extra allocations over
+// runTask's own baseline (the one alloc/op in both is Task.Execute's
+// TaskResult, unrelated to the Observer path): the OnStart/OnFinish calls are
+// skipped entirely rather than merely becoming no-ops.
+func BenchmarkRunTaskNoObserver(b *testing.B) {
+	p := NewPool(context.Background(), Option{NumberWorker: 1, DisableAutoStart: true})
+	defer p.Stop()
+
+	task := NewTask(p.ctx, benchNoopExecutor)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.runTask(task)
+		// drain the buffered result so the next iteration's Execute can send
+		// into the same channel instead of blocking; reusing it here (rather
+		// than reallocating task.future per iteration) keeps channel setup
+		// out of the measured region.
+		<-task.future
+	}
+}
+
+func BenchmarkRunTaskWithObserver(b *testing.B) {
+	p := NewPool(context.Background(), Option{NumberWorker: 1, DisableAutoStart: true, Observer: &countingObserver{}})
+	defer p.Stop()
+
+	task := NewTask(p.ctx, benchNoopExecutor)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.runTask(task)
+		<-task.future
+	}
+}
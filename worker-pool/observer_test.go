@@ -0,0 +1,138 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingObserver struct {
+	enqueued, started, finished, rejected, spawned, exited int32
+}
+
+func (o *countingObserver) OnEnqueue()                    { atomic.AddInt32(&o.enqueued, 1) }
+func (o *countingObserver) OnStart()                      { atomic.AddInt32(&o.started, 1) }
+func (o *countingObserver) OnFinish(time.Duration, error) { atomic.AddInt32(&o.finished, 1) }
+func (o *countingObserver) OnReject()                     { atomic.AddInt32(&o.rejected, 1) }
+func (o *countingObserver) OnWorkerSpawn()                { atomic.AddInt32(&o.spawned, 1) }
+func (o *countingObserver) OnWorkerExit()                 { atomic.AddInt32(&o.exited, 1) }
+
+func TestObserverHooks(t *testing.T) {
+	obs := &countingObserver{}
+	p := NewPool(context.Background(), Option{NumberWorker: 2, Observer: obs})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		p.Execute(func(ctx context.Context) (interface{}, error) {
+			defer wg.Done()
+			return nil, nil
+		})
+	}
+	wg.Wait()
+	p.Stop()
+
+	if got := atomic.LoadInt32(&obs.enqueued); got != 5 {
+		t.Fatalf("expected 5 enqueues, got %d", got)
+	}
+	if got := atomic.LoadInt32(&obs.finished); got != 5 {
+		t.Fatalf("expected 5 finishes, got %d", got)
+	}
+	if got := atomic.LoadInt32(&obs.spawned); got != 2 {
+		t.Fatalf("expected 2 worker spawns, got %d", got)
+	}
+	if got := atomic.LoadInt32(&obs.exited); got != 2 {
+		t.Fatalf("expected 2 worker exits after Stop, got %d", got)
+	}
+}
+
+// errObserver records whether OnFinish ever reported a nil error.
+type errObserver struct {
+	sawNilErr int32
+}
+
+func (o *errObserver) OnEnqueue() {}
+func (o *errObserver) OnStart()   {}
+func (o *errObserver) OnFinish(_ time.Duration, err error) {
+	if err == nil {
+		atomic.AddInt32(&o.sawNilErr, 1)
+	}
+}
+func (o *errObserver) OnReject()      {}
+func (o *errObserver) OnWorkerSpawn() {}
+func (o *errObserver) OnWorkerExit()  {}
+
+// TestObserverOnFinishSeesErrorUnderConcurrentResultRead reproduces a race
+// between runTask's post-Execute error lookup and a caller draining the same
+// task's Result() channel: the Observer's OnFinish must still report the
+// task's real error even when something else reads Result() first.
+func TestObserverOnFinishSeesErrorUnderConcurrentResultRead(t *testing.T) {
+	obs := &errObserver{}
+	p := NewPool(context.Background(), Option{NumberWorker: 8, Observer: obs})
+	defer p.Stop()
+
+	const n = 500
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		task := NewTask(context.Background(), func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("boom")
+		})
+		go func() {
+			defer wg.Done()
+			<-task.Result()
+		}()
+		p.Do(task)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&obs.sawNilErr); got != 0 {
+		t.Fatalf("expected OnFinish to always see the task's error, but saw nil error %d/%d times", got, n)
+	}
+}
+
+func TestStats(t *testing.T) {
+	p := NewPool(context.Background(), Option{NumberWorker: 1})
+	defer p.Stop()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		p.Execute(func(ctx context.Context) (interface{}, error) {
+			defer wg.Done()
+			return nil, nil
+		})
+	}
+	wg.Wait()
+
+	// give the worker a moment to record the last finish.
+	time.Sleep(10 * time.Millisecond)
+
+	stats := p.Stats()
+	if stats.TasksAccepted != 3 {
+		t.Fatalf("expected 3 accepted, got %d", stats.TasksAccepted)
+	}
+	if stats.TasksCompleted != 3 {
+		t.Fatalf("expected 3 completed, got %d", stats.TasksCompleted)
+	}
+	if stats.CoreWorkers != 1 {
+		t.Fatalf("expected 1 core worker, got %d", stats.CoreWorkers)
+	}
+}
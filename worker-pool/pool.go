@@ -16,6 +16,8 @@ package workerpool
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -37,6 +39,9 @@ type TaskResult struct {
 // Task represents a task.
 type Task struct {
 	ctx      context.Context
+	timeout  time.Duration
+	priority int
+	group    string
 	executor func(context.Context) (interface{}, error)
 	future   chan *TaskResult
 }
@@ -50,16 +55,109 @@ func NewTask(ctx context.Context, executor func(context.Context) (interface{}, e
 	}
 }
 
-// Execute task.
-func (t *Task) Execute() {
-	var result interface{}
-	var err error
+// NewTaskWithTimeout creates a new task whose executor is bound by timeout. Unlike
+// passing a context that already carries a deadline, this timeout is enforced by
+// Execute itself, independently of whatever context the task ends up running
+// under (e.g. after being re-queued onto a different pool).
+func NewTaskWithTimeout(ctx context.Context, timeout time.Duration, executor func(context.Context) (interface{}, error)) *Task {
+	t := NewTask(ctx, executor)
+	t.timeout = timeout
+	return t
+}
+
+// NewTaskWithPriority creates a new task carrying priority, for use with a
+// Pool backed by PriorityScheduler. Higher priority values are dispatched
+// first; it has no effect under the default FIFO scheduler.
+func NewTaskWithPriority(ctx context.Context, priority int, executor func(context.Context) (interface{}, error)) *Task {
+	t := NewTask(ctx, executor)
+	t.priority = priority
+	return t
+}
+
+// NewTaskWithGroup creates a new task tagged with group, for use with a Pool
+// backed by WeightedFairScheduler. It has no effect under other schedulers.
+func NewTaskWithGroup(ctx context.Context, group string, executor func(context.Context) (interface{}, error)) *Task {
+	t := NewTask(ctx, executor)
+	t.group = group
+	return t
+}
 
-	if t.executor != nil {
-		result, err = t.executor(t.ctx)
+// Execute task. A panicking executor is recovered and reported via TaskResult.Err
+// instead of crashing the worker that runs it. It returns the same TaskResult
+// that is pushed onto Result(), for callers (such as Pool itself) that need
+// it without racing a concurrent Result() reader.
+func (t *Task) Execute() (res *TaskResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			res = &TaskResult{Err: fmt.Errorf("workerpool: task panicked: %v", r)}
+			select {
+			case t.future <- res:
+			default:
+			}
+		}
+	}()
+
+	if t.timeout <= 0 {
+		var result interface{}
+		var err error
+
+		if t.executor != nil {
+			result, err = t.executor(t.ctx)
+		}
+
+		res = &TaskResult{Result: result, Err: err}
+		t.future <- res
+		return res
 	}
 
-	t.future <- &TaskResult{Result: result, Err: err}
+	res = t.executeWithTimeout()
+	return res
+}
+
+// executeWithTimeout runs the executor under a context.WithTimeout derived from
+// t.ctx. If the deadline fires before the executor returns, the caller is
+// unblocked immediately with context.DeadlineExceeded, while a background
+// goroutine keeps draining the executor's eventual result so a late error can
+// be logged instead of leaking the goroutine.
+func (t *Task) executeWithTimeout() *TaskResult {
+	ctx, cancel := context.WithTimeout(t.ctx, t.timeout)
+	defer cancel()
+
+	done := make(chan *TaskResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- &TaskResult{Err: fmt.Errorf("workerpool: task panicked: %v", r)}
+			}
+		}()
+
+		var result interface{}
+		var err error
+		if t.executor != nil {
+			result, err = t.executor(ctx)
+		}
+		done <- &TaskResult{Result: result, Err: err}
+	}()
+
+	select {
+	case res := <-done:
+		t.future <- res
+		return res
+
+	case <-ctx.Done():
+		res := &TaskResult{Err: ctx.Err()}
+		t.future <- res
+
+		// the executor is still running; keep consuming its result so the
+		// goroutine above doesn't block forever on an unread channel, and
+		// log a late error since nobody is listening on Result() anymore.
+		go func() {
+			if res := <-done; res.Err != nil {
+				log.Printf("workerpool: task finished after its %s deadline with error: %v", t.timeout, res.Err)
+			}
+		}()
+		return res
+	}
 }
 
 // Result pushed via channel
@@ -76,10 +174,22 @@ type Option struct {
 	NumberWorker int `yaml:"number_worker" json:"number_worker"`
 	// ExpandableLimit limits number of workers to be expanded on demand.
 	// Default: 0 (no expandable)
+	//
+	// Expansion is triggered by Do when scheduler.TryPush(t) reports the
+	// queue is full. The default FIFO scheduler is bounded, so this fires
+	// under load as expected; PriorityScheduler and WeightedFairScheduler are
+	// unbounded and their TryPush never returns false, so ExpandableLimit has
+	// no effect with either of them.
 	ExpandableLimit int32 `yaml:"expandable_limit" json:"expandable_limit"`
 	// ExpandedLifetime represents lifetime of expanded worker (in nanoseconds).
 	// Default: 1 minute
 	ExpandedLifetime time.Duration `yaml:"expanded_lifetime" json:"expanded_lifetime"`
+	// Scheduler decides the order tasks are handed to workers.
+	// Default: a FIFO channel of size 1 (Pool's original behavior).
+	Scheduler Scheduler `yaml:"-" json:"-"`
+	// Observer, if set, receives Pool's lifecycle events.
+	// Default: none.
+	Observer Observer `yaml:"-" json:"-"`
 }
 
 func (o *Option) normalize() {
@@ -103,9 +213,11 @@ type Pool struct {
 
 	opt Option
 
-	wg        sync.WaitGroup
-	taskQueue chan *Task
-	expanded  int32
+	wg          sync.WaitGroup
+	scheduler   Scheduler
+	expanded    int32
+	coreWorkers int32
+	stats       poolStats
 
 	state uint32 // 0: not start, 1: started, 2: stopped
 }
@@ -119,10 +231,15 @@ func NewPool(ctx context.Context, opt Option) (p *Pool) {
 	// normalize option
 	opt.normalize()
 
+	scheduler := opt.Scheduler
+	if scheduler == nil {
+		scheduler = newFIFOScheduler(1)
+	}
+
 	// set up pool
 	p = &Pool{
 		opt:       opt,
-		taskQueue: make(chan *Task, 1),
+		scheduler: scheduler,
 	}
 	p.ctx, p.cancel = context.WithCancel(ctx)
 
@@ -141,6 +258,7 @@ func (p *Pool) Start() {
 
 		p.wg.Add(numWorker)
 		for i := 0; i < numWorker; i++ {
+			p.onWorkerSpawn()
 			go p.worker()
 		}
 	}
@@ -153,7 +271,7 @@ func (p *Pool) Stop() {
 		p.cancel()
 
 		// wait child workers
-		close(p.taskQueue)
+		p.scheduler.Close()
 		p.wg.Wait()
 	}
 }
@@ -173,6 +291,27 @@ func (p *Pool) ExecuteWithCtx(ctx context.Context, exec func(context.Context) (i
 	return
 }
 
+// ExecuteWithTimeout executes a task bound by timeout, see NewTaskWithTimeout.
+func (p *Pool) ExecuteWithTimeout(timeout time.Duration, exec func(context.Context) (interface{}, error)) (t *Task) {
+	t = NewTaskWithTimeout(p.ctx, timeout, exec)
+	p.Do(t)
+	return
+}
+
+// ExecuteWithPriority executes a task carrying priority, see NewTaskWithPriority.
+func (p *Pool) ExecuteWithPriority(priority int, exec func(context.Context) (interface{}, error)) (t *Task) {
+	t = NewTaskWithPriority(p.ctx, priority, exec)
+	p.Do(t)
+	return
+}
+
+// ExecuteWithGroup executes a task tagged with group, see NewTaskWithGroup.
+func (p *Pool) ExecuteWithGroup(group string, exec func(context.Context) (interface{}, error)) (t *Task) {
+	t = NewTaskWithGroup(p.ctx, group, exec)
+	p.Do(t)
+	return
+}
+
 // TryExecute tries to execute a task. If task queue is full, returns immediately and
 // addedToQueue is false.
 func (p *Pool) TryExecute(exec func(context.Context) (interface{}, error)) (t *Task, addedToQueue bool) {
@@ -200,11 +339,13 @@ func (p *Pool) Do(t *Task) {
 		if p.opt.ExpandableLimit == 0 {
 			p.push(t)
 		} else {
-			select {
-			case p.taskQueue <- t:
-			default:
+			if p.scheduler.TryPush(t) {
+				p.stats.recordAccept()
+				p.onEnqueue()
+			} else {
 				if atomic.AddInt32(&p.expanded, 1) <= p.opt.ExpandableLimit {
 					p.wg.Add(1)
+					p.onWorkerSpawn()
 					go p.expandedWorker()
 				} else {
 					atomic.AddInt32(&p.expanded, -1)
@@ -218,14 +359,19 @@ func (p *Pool) Do(t *Task) {
 }
 
 func (p *Pool) push(t *Task) {
-	select {
-	case <-p.ctx.Done():
-		t.future <- &TaskResult{Err: p.ctx.Err()}
+	if p.scheduler.Push(t.ctx, p.ctx.Done(), t) {
+		p.stats.recordAccept()
+		p.onEnqueue()
+		return
+	}
 
-	case <-t.ctx.Done():
-		t.future <- &TaskResult{Err: t.ctx.Err()}
+	p.stats.recordReject()
+	p.onReject()
 
-	case p.taskQueue <- t:
+	if p.ctx.Err() != nil {
+		t.future <- &TaskResult{Err: p.ctx.Err()}
+	} else {
+		t.future <- &TaskResult{Err: t.ctx.Err()}
 	}
 }
 
@@ -239,51 +385,69 @@ func (p *Pool) TryDo(t *Task) (addedToQueue bool) {
 
 		select {
 		case <-p.ctx.Done():
+			p.stats.recordReject()
+			p.onReject()
 			t.future <- &TaskResult{Err: p.ctx.Err()}
 
 		case <-t.ctx.Done():
+			p.stats.recordReject()
+			p.onReject()
 			t.future <- &TaskResult{Err: t.ctx.Err()}
 
-		case p.taskQueue <- t:
-			addedToQueue = true
-
 		default:
+			addedToQueue = p.scheduler.TryPush(t)
+			if addedToQueue {
+				p.stats.recordAccept()
+				p.onEnqueue()
+			} else {
+				p.stats.recordReject()
+				p.onReject()
+			}
 		}
 	}
 	return
 }
 
+// worker drains the scheduler until it is closed by Stop. Execute recovers its
+// own panics, so a misbehaving executor never takes the worker down with it.
 func (p *Pool) worker() {
-	for task := range p.taskQueue {
-		task.Execute()
+	atomic.AddInt32(&p.coreWorkers, 1)
+	defer func() {
+		atomic.AddInt32(&p.coreWorkers, -1)
+		p.onWorkerExit()
+		p.wg.Done()
+	}()
+
+	for {
+		task, _, closed := p.scheduler.Pop(0)
+		if closed {
+			break
+		}
+		p.runTask(task)
 	}
-	p.wg.Done()
 }
 
+// expandedWorker behaves like worker but exits once it has been idle for
+// ExpandedLifetime. Same panic-safety guarantee as worker applies here.
 func (p *Pool) expandedWorker() {
 	lifetime := p.opt.ExpandedLifetime
-	timer := time.NewTimer(lifetime)
 	defer func() {
-		p.wg.Done()
 		atomic.AddInt32(&p.expanded, -1)
+		p.onWorkerExit()
+		p.wg.Done()
 	}()
 
 	for {
-		select {
-		case task, ok := <-p.taskQueue:
-			stopTimer(timer)
-
-			if !ok {
-				return
-			}
-
-			// execute task and expand the lifetime
-			task.Execute()
-			timer.Reset(lifetime)
-
-		case <-timer.C:
+		task, gotTask, closed := p.scheduler.Pop(lifetime)
+		if closed {
+			return
+		}
+		if !gotTask {
+			// idle for a full lifetime; let this expanded worker exit
 			return
 		}
+
+		p.runTask(task)
 	}
 }
 
@@ -0,0 +1,78 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskTimeout(t *testing.T) {
+	p := NewPool(context.Background(), Option{NumberWorker: 1})
+	defer p.Stop()
+
+	task := p.ExecuteWithTimeout(10*time.Millisecond, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond) // simulate late completion after the deadline
+		return nil, errors.New("too late")
+	})
+
+	res := <-task.Result()
+	if !errors.Is(res.Err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", res.Err)
+	}
+}
+
+func TestTaskPanicIsRecovered(t *testing.T) {
+	p := NewPool(context.Background(), Option{NumberWorker: 1})
+	defer p.Stop()
+
+	task := p.Execute(func(ctx context.Context) (interface{}, error) {
+		panic("boom")
+	})
+
+	res := <-task.Result()
+	if res.Err == nil {
+		t.Fatal("expected panic to be reported as an error")
+	}
+
+	// the worker must still be alive after the panic.
+	task2 := p.Execute(func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	})
+	if res2 := <-task2.Result(); res2.Err != nil || res2.Result != "ok" {
+		t.Fatalf("worker did not survive the panic: %+v", res2)
+	}
+}
+
+func TestTaskTimeoutWithPanicInBackground(t *testing.T) {
+	p := NewPool(context.Background(), Option{NumberWorker: 1})
+	defer p.Stop()
+
+	task := p.ExecuteWithTimeout(10*time.Millisecond, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		panic("late boom")
+	})
+
+	res := <-task.Result()
+	if !errors.Is(res.Err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", res.Err)
+	}
+
+	// give the background continuation a moment to drain without crashing the test binary.
+	time.Sleep(20 * time.Millisecond)
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PriorityScheduler dispatches tasks according to the integer priority
+// attached via NewTaskWithPriority; higher priority values are dispatched
+// first. Tasks of equal priority are dispatched in the order they were
+// pushed. It is unbounded: Push never blocks, and workers block cheaply on a
+// condition variable while the queue is empty. Because TryPush never reports
+// the queue as full, Option.ExpandableLimit has no effect when this is the
+// configured Scheduler.
+type PriorityScheduler struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	heap   priorityHeap
+	seq    int64
+	closed bool
+}
+
+// NewPriorityScheduler creates an empty PriorityScheduler.
+func NewPriorityScheduler() *PriorityScheduler {
+	s := &PriorityScheduler{}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// TryPush enqueues t. It always succeeds unless the scheduler has been closed.
+func (s *PriorityScheduler) TryPush(t *Task) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	s.seq++
+	heap.Push(&s.heap, &priorityItem{task: t, priority: t.priority, seq: s.seq})
+	s.cond.Broadcast()
+	return true
+}
+
+// Push enqueues t. Since the scheduler is unbounded, it never actually
+// blocks; ctx and stop are accepted to satisfy Scheduler.
+func (s *PriorityScheduler) Push(ctx context.Context, stop <-chan struct{}, t *Task) bool {
+	return s.TryPush(t)
+}
+
+// Pop returns the highest-priority task, blocking until one is available,
+// timeout elapses, or the scheduler is closed and drained.
+func (s *PriorityScheduler) Pop(timeout time.Duration) (t *Task, gotTask bool, closed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var timedOut int32
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			atomic.StoreInt32(&timedOut, 1)
+			s.mu.Lock()
+			s.cond.Broadcast()
+			s.mu.Unlock()
+		})
+		defer timer.Stop()
+	}
+
+	for s.heap.Len() == 0 && !s.closed {
+		if timeout > 0 && atomic.LoadInt32(&timedOut) == 1 {
+			return nil, false, false
+		}
+		s.cond.Wait()
+	}
+
+	if s.heap.Len() > 0 {
+		item := heap.Pop(&s.heap).(*priorityItem)
+		return item.task, true, false
+	}
+	return nil, false, true
+}
+
+// Close stops the scheduler, waking any worker blocked in Pop.
+func (s *PriorityScheduler) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// Len reports the number of tasks currently queued.
+func (s *PriorityScheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heap.Len()
+}
+
+type priorityItem struct {
+	task     *Task
+	priority int
+	seq      int64
+}
+
+// priorityHeap orders by priority descending, then by seq ascending (FIFO
+// among equal priorities).
+type priorityHeap []*priorityItem
+
+func (h priorityHeap) Len() int { return len(h) }
+
+func (h priorityHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h priorityHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *priorityHeap) Push(x interface{}) {
+	*h = append(*h, x.(*priorityItem))
+}
+
+func (h *priorityHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPriorityScheduler(t *testing.T) {
+	p := NewPool(context.Background(), Option{
+		NumberWorker:     1,
+		DisableAutoStart: true,
+		Scheduler:        NewPriorityScheduler(),
+	})
+
+	order := make(chan int, 3)
+	p.ExecuteWithPriority(1, func(ctx context.Context) (interface{}, error) { order <- 1; return nil, nil })
+	p.ExecuteWithPriority(5, func(ctx context.Context) (interface{}, error) { order <- 5; return nil, nil })
+	p.ExecuteWithPriority(3, func(ctx context.Context) (interface{}, error) { order <- 3; return nil, nil })
+
+	p.Start()
+	defer p.Stop()
+
+	want := []int{5, 3, 1}
+	for _, w := range want {
+		select {
+		case got := <-order:
+			if got != w {
+				t.Fatalf("expected priority %d next, got %d", w, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for task")
+		}
+	}
+}
+
+func TestPriorityScheduler_PopTimeout(t *testing.T) {
+	s := NewPriorityScheduler()
+	start := time.Now()
+	task, gotTask, closed := s.Pop(10 * time.Millisecond)
+	if task != nil || gotTask || closed {
+		t.Fatalf("expected timeout with no task, got %+v %v %v", task, gotTask, closed)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Fatal("Pop returned before the timeout elapsed")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package prom adapts workerpool.Observer to Prometheus metrics, so a Pool
+// can be wired into existing dashboards without modifying core code.
+package prom
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	workerpool "github.com/JohnTitor/garr/worker-pool"
+)
+
+// Observer implements workerpool.Observer, recording pool activity as
+// Prometheus counters, a gauge, and a latency histogram.
+type Observer struct {
+	enqueued     prometheus.Counter
+	started      prometheus.Counter
+	completed    prometheus.Counter
+	rejected     prometheus.Counter
+	workersAlive prometheus.Gauge
+	latency      prometheus.Histogram
+}
+
+// New creates an Observer and registers its metrics with reg under
+// <namespace>_<subsystem>_*, following the usual Prometheus naming
+// convention.
+func New(reg prometheus.Registerer, namespace, subsystem string) *Observer {
+	o := &Observer{
+		enqueued: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "tasks_enqueued_total",
+			Help:      "Total number of tasks accepted onto the pool.",
+		}),
+		started: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "tasks_started_total",
+			Help:      "Total number of tasks a worker began executing.",
+		}),
+		completed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "tasks_completed_total",
+			Help:      "Total number of tasks that finished executing.",
+		}),
+		rejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "tasks_rejected_total",
+			Help:      "Total number of tasks rejected instead of accepted.",
+		}),
+		workersAlive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "workers_alive",
+			Help:      "Current number of live worker goroutines, core and expanded.",
+		}),
+		latency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "task_duration_seconds",
+			Help:      "Task execution duration in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+
+	reg.MustRegister(o.enqueued, o.started, o.completed, o.rejected, o.workersAlive, o.latency)
+
+	return o
+}
+
+// OnEnqueue implements workerpool.Observer.
+func (o *Observer) OnEnqueue() { o.enqueued.Inc() }
+
+// OnStart implements workerpool.Observer.
+func (o *Observer) OnStart() { o.started.Inc() }
+
+// OnFinish implements workerpool.Observer.
+func (o *Observer) OnFinish(dur time.Duration, err error) {
+	o.completed.Inc()
+	o.latency.Observe(dur.Seconds())
+}
+
+// OnReject implements workerpool.Observer.
+func (o *Observer) OnReject() { o.rejected.Inc() }
+
+// OnWorkerSpawn implements workerpool.Observer.
+func (o *Observer) OnWorkerSpawn() { o.workersAlive.Inc() }
+
+// OnWorkerExit implements workerpool.Observer.
+func (o *Observer) OnWorkerExit() { o.workersAlive.Dec() }
+
+var _ workerpool.Observer = (*Observer)(nil)
@@ -0,0 +1,129 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Scheduler decides the order in which queued tasks are handed out to
+// workers. Pool uses a bounded FIFO channel by default (see Option); ship
+// PriorityScheduler or WeightedFairScheduler instead for priority- or
+// tenant-aware dispatch.
+type Scheduler interface {
+	// Push enqueues t, blocking until it is accepted, ctx is done, or stop is
+	// closed.
+	Push(ctx context.Context, stop <-chan struct{}, t *Task) (ok bool)
+	// TryPush enqueues t without blocking.
+	TryPush(t *Task) (ok bool)
+	// Pop blocks until a task is ready, timeout elapses (if timeout > 0), or
+	// the scheduler has been closed and drained. closed is true only in the
+	// latter case.
+	Pop(timeout time.Duration) (t *Task, gotTask bool, closed bool)
+	// Close stops the scheduler; blocked and future Pop calls drain whatever
+	// is left, then report closed once empty.
+	Close()
+	// Len reports the number of tasks currently queued.
+	Len() int
+}
+
+// newFIFOScheduler is the default Scheduler: a single buffered channel,
+// behaviorally identical to the channel Pool used directly before Scheduler
+// was introduced.
+func newFIFOScheduler(size int) Scheduler {
+	return &fifoScheduler{ch: make(chan *Task, size)}
+}
+
+type fifoScheduler struct {
+	// closeMu is held for read by Push/TryPush while they may send on ch, and
+	// for write by Close while it closes ch, so a send can never race a
+	// close: Close blocks until every in-flight Push/TryPush has returned.
+	closeMu sync.RWMutex
+	closed  bool
+	ch      chan *Task
+}
+
+func (s *fifoScheduler) Push(ctx context.Context, stop <-chan struct{}, t *Task) bool {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	if s.closed {
+		return false
+	}
+
+	select {
+	case s.ch <- t:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-stop:
+		return false
+	}
+}
+
+func (s *fifoScheduler) TryPush(t *Task) bool {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	if s.closed {
+		return false
+	}
+
+	select {
+	case s.ch <- t:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *fifoScheduler) Pop(timeout time.Duration) (t *Task, gotTask bool, closed bool) {
+	if timeout <= 0 {
+		task, ok := <-s.ch
+		if !ok {
+			return nil, false, true
+		}
+		return task, true, false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer stopTimer(timer)
+
+	select {
+	case task, ok := <-s.ch:
+		if !ok {
+			return nil, false, true
+		}
+		return task, true, false
+	case <-timer.C:
+		return nil, false, false
+	}
+}
+
+func (s *fifoScheduler) Close() {
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+func (s *fifoScheduler) Len() int {
+	return len(s.ch)
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron builds a Trigger from a standard 5-field cron expression
+// ("minute hour day-of-month month day-of-week"). Each field accepts "*",
+// single values, comma-separated lists, ranges ("a-b") and step values
+// ("*/n" or "a-b/n"). It returns an error instead of a Trigger if spec cannot
+// be parsed.
+func Cron(spec string) (Trigger, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("scheduler: cron spec %q must have 5 fields, got %d", spec, len(fields))
+	}
+
+	minute, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: minute field: %w", err)
+	}
+	hour, err := parseCronField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: hour field: %w", err)
+	}
+	domStr := fields[2]
+	dom, err := parseCronField(domStr, 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: day-of-month field: %w", err)
+	}
+	month, err := parseCronField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: month field: %w", err)
+	}
+	dowStr := fields[4]
+	dow, err := parseCronField(dowStr, 0, 6)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: day-of-week field: %w", err)
+	}
+
+	return cronTrigger{
+		minute: minute, hour: hour, dom: dom, month: month, dow: dow,
+		domRestricted: domStr != "*",
+		dowRestricted: dowStr != "*",
+	}, nil
+}
+
+type cronTrigger struct {
+	minute, hour, dom, month, dow map[int]struct{}
+
+	// domRestricted and dowRestricted record whether day-of-month and
+	// day-of-week were given as "*". Per standard cron semantics, when both
+	// fields are restricted they are OR'd together rather than AND'd.
+	domRestricted, dowRestricted bool
+}
+
+func (t cronTrigger) next(from time.Time) time.Time {
+	// walk forward minute-by-minute starting at the next whole minute; cron
+	// resolution is one minute, so this terminates within a few years at
+	// worst; if nothing matches by then, the last minute examined is returned.
+	next := from.Truncate(time.Minute).Add(time.Minute)
+	for i := 0; i < 5*366*24*60; i++ {
+		if _, ok := t.month[int(next.Month())]; ok {
+			if t.dayMatches(next) {
+				if _, ok := t.hour[next.Hour()]; ok {
+					if _, ok := t.minute[next.Minute()]; ok {
+						return next
+					}
+				}
+			}
+		}
+		next = next.Add(time.Minute)
+	}
+	return next
+}
+
+// dayMatches reports whether next satisfies the day-of-month/day-of-week
+// constraint. When only one of the two fields is restricted, that field
+// alone decides. When both are restricted, standard cron ORs them: the day
+// matches if either field matches.
+func (t cronTrigger) dayMatches(next time.Time) bool {
+	_, domOK := t.dom[next.Day()]
+	_, dowOK := t.dow[int(next.Weekday())]
+
+	if t.domRestricted && t.dowRestricted {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
+
+func parseCronField(field string, min, max int) (map[int]struct{}, error) {
+	values := make(map[int]struct{})
+
+	for _, part := range strings.Split(field, ",") {
+		rangeStr, step, err := splitStep(part)
+		if err != nil {
+			return nil, err
+		}
+
+		lo, hi := min, max
+		if rangeStr != "*" {
+			if strings.Contains(rangeStr, "-") {
+				bounds := strings.SplitN(rangeStr, "-", 2)
+				lo, err = strconv.Atoi(bounds[0])
+				if err != nil {
+					return nil, fmt.Errorf("invalid range %q: %w", part, err)
+				}
+				hi, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid range %q: %w", part, err)
+				}
+			} else {
+				v, err := strconv.Atoi(rangeStr)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value %q: %w", part, err)
+				}
+				lo, hi = v, v
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("value %q out of range [%d, %d]", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			values[v] = struct{}{}
+		}
+	}
+
+	return values, nil
+}
+
+// splitStep splits "a-b/n" or "*/n" into ("a-b" or "*", n), defaulting step to 1.
+func splitStep(part string) (rangeStr string, step int, err error) {
+	pieces := strings.SplitN(part, "/", 2)
+	if len(pieces) == 1 {
+		return pieces[0], 1, nil
+	}
+
+	step, err = strconv.Atoi(pieces[1])
+	if err != nil || step <= 0 {
+		return "", 0, fmt.Errorf("invalid step %q", part)
+	}
+	return pieces[0], step, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// job is a single registered schedule entry. It is heap-ordered by next.
+type job struct {
+	trigger Trigger
+	policy  OverrunPolicy
+	exec    func(context.Context) (interface{}, error)
+
+	mu        sync.Mutex
+	next      time.Time
+	paused    bool
+	canceled  bool
+	running   bool
+	cancelRun context.CancelFunc
+
+	index int // maintained by container/heap
+}
+
+// JobHandle controls a job registered with Scheduler.
+type JobHandle struct {
+	job *job
+	s   *Scheduler
+}
+
+// Pause prevents the job from firing until Resume is called. A fire that is
+// already in flight is not affected.
+func (h *JobHandle) Pause() {
+	h.job.mu.Lock()
+	h.job.paused = true
+	h.job.mu.Unlock()
+}
+
+// Resume re-enables a paused job.
+func (h *JobHandle) Resume() {
+	h.job.mu.Lock()
+	h.job.paused = false
+	h.job.mu.Unlock()
+}
+
+// Cancel removes the job from the scheduler. A run already in flight is left
+// to finish; it will not be re-armed afterwards.
+func (h *JobHandle) Cancel() {
+	h.job.mu.Lock()
+	h.job.canceled = true
+	h.job.mu.Unlock()
+	h.s.poke()
+}
+
+// NextRun reports the job's next scheduled fire time.
+func (h *JobHandle) NextRun() time.Time {
+	h.job.mu.Lock()
+	defer h.job.mu.Unlock()
+	return h.job.next
+}
+
+// jobHeap implements container/heap.Interface, ordering jobs by next fire time.
+type jobHeap []*job
+
+func (h jobHeap) Len() int           { return len(h) }
+func (h jobHeap) Less(i, j int) bool { return h[i].next.Before(h[j].next) }
+func (h jobHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *jobHeap) Push(x interface{}) {
+	j := x.(*job)
+	j.index = len(*h)
+	*h = append(*h, j)
+}
+
+func (h *jobHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	j.index = -1
+	*h = old[:n-1]
+	return j
+}
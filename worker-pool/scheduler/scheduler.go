@@ -0,0 +1,237 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package scheduler registers recurring jobs that are dispatched into an
+// existing workerpool.Pool on a schedule (fixed interval, daily clock time or
+// cron expression), in the spirit of gocron/asynq-style schedulers.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	workerpool "github.com/JohnTitor/garr/worker-pool"
+)
+
+// OverrunPolicy decides what happens when a job's next fire time arrives
+// while its previous run is still executing.
+type OverrunPolicy int
+
+const (
+	// Skip drops the new fire; the previous run is left to finish on its own.
+	Skip OverrunPolicy = iota
+	// Queue submits the new run regardless, so both may execute concurrently.
+	Queue
+	// Replace cancels the still-running previous instance before submitting
+	// the new one.
+	Replace
+)
+
+// Scheduler dispatches registered jobs into a *workerpool.Pool according to
+// their Trigger. A single goroutine maintains a min-heap of jobs keyed by
+// next fire time and wakes up only when the next job is due.
+type Scheduler struct {
+	pool *workerpool.Pool
+
+	mu   sync.Mutex
+	jobs jobHeap
+	wake chan struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{}
+}
+
+// New creates a Scheduler that submits due jobs onto pool.
+func New(pool *workerpool.Pool) *Scheduler {
+	s := &Scheduler{
+		pool:   pool,
+		wake:   make(chan struct{}, 1),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Every registers exec to run every d, starting d from now.
+func (s *Scheduler) Every(d time.Duration, policy OverrunPolicy, exec func(context.Context) (interface{}, error)) *JobHandle {
+	return s.register(Every(d), policy, exec)
+}
+
+// At registers exec to run once per day at the wall-clock time carried by clock.
+func (s *Scheduler) At(clock time.Time, policy OverrunPolicy, exec func(context.Context) (interface{}, error)) *JobHandle {
+	return s.register(At(clock), policy, exec)
+}
+
+// Cron registers exec to run according to the given cron expression.
+func (s *Scheduler) Cron(spec string, policy OverrunPolicy, exec func(context.Context) (interface{}, error)) (*JobHandle, error) {
+	trigger, err := Cron(spec)
+	if err != nil {
+		return nil, err
+	}
+	return s.register(trigger, policy, exec), nil
+}
+
+func (s *Scheduler) register(trigger Trigger, policy OverrunPolicy, exec func(context.Context) (interface{}, error)) *JobHandle {
+	j := &job{
+		trigger: trigger,
+		policy:  policy,
+		exec:    exec,
+		next:    trigger.next(time.Now()),
+	}
+
+	s.mu.Lock()
+	heap.Push(&s.jobs, j)
+	s.mu.Unlock()
+
+	s.poke()
+
+	return &JobHandle{job: j, s: s}
+}
+
+// poke wakes the run loop so it can recompute how long to sleep after a job
+// is added or canceled.
+func (s *Scheduler) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops the scheduler and blocks until its run loop has exited. Jobs
+// that are currently running are not canceled; no further fires are
+// dispatched.
+func (s *Scheduler) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+	<-s.done
+}
+
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		stopTimer(timer)
+		timer.Reset(s.sleepDuration())
+
+		select {
+		case <-s.closed:
+			return
+		case <-s.wake:
+			continue
+		case <-timer.C:
+			s.fireDue()
+		}
+	}
+}
+
+func (s *Scheduler) sleepDuration() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jobs.Len() == 0 {
+		return time.Hour
+	}
+	if d := time.Until(s.jobs[0].next); d > 0 {
+		return d
+	}
+	return 0
+}
+
+func (s *Scheduler) fireDue() {
+	now := time.Now()
+	for {
+		s.mu.Lock()
+		if s.jobs.Len() == 0 || s.jobs[0].next.After(now) {
+			s.mu.Unlock()
+			return
+		}
+		j := heap.Pop(&s.jobs).(*job)
+		s.mu.Unlock()
+
+		j.mu.Lock()
+		canceled := j.canceled
+		paused := j.paused
+		if !canceled {
+			// advance from the previous fire time so the schedule doesn't
+			// drift; if the loop fell behind, resume from now instead of
+			// replaying every missed fire in a burst.
+			next := j.trigger.next(j.next)
+			if !next.After(now) {
+				next = j.trigger.next(now)
+			}
+			j.next = next
+		}
+		j.mu.Unlock()
+
+		if !canceled && !paused {
+			s.dispatch(j)
+		}
+		if !canceled {
+			s.mu.Lock()
+			heap.Push(&s.jobs, j)
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *Scheduler) dispatch(j *job) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	switch j.policy {
+	case Skip:
+		if j.running {
+			return
+		}
+	case Replace:
+		if j.running && j.cancelRun != nil {
+			j.cancelRun()
+		}
+	case Queue:
+		// both the previous and new run are allowed to execute concurrently.
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancelRun = cancel
+	j.running = true
+
+	t := workerpool.NewTask(ctx, j.exec)
+	if !s.pool.TryDo(t) {
+		j.running = false
+		cancel()
+		return
+	}
+
+	go func() {
+		<-t.Result()
+		j.mu.Lock()
+		j.running = false
+		j.mu.Unlock()
+	}()
+}
+
+func stopTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	workerpool "github.com/JohnTitor/garr/worker-pool"
+)
+
+func TestEveryFires(t *testing.T) {
+	pool := workerpool.NewPool(context.Background(), workerpool.Option{NumberWorker: 1})
+	defer pool.Stop()
+
+	s := New(pool)
+	defer s.Close()
+
+	var count int32
+	s.Every(5*time.Millisecond, Skip, func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&count, 1)
+		return nil, nil
+	})
+
+	time.Sleep(60 * time.Millisecond)
+	if atomic.LoadInt32(&count) < 2 {
+		t.Fatalf("expected at least 2 fires, got %d", count)
+	}
+}
+
+func TestPauseResumeCancel(t *testing.T) {
+	pool := workerpool.NewPool(context.Background(), workerpool.Option{NumberWorker: 1})
+	defer pool.Stop()
+
+	s := New(pool)
+	defer s.Close()
+
+	var count int32
+	h := s.Every(5*time.Millisecond, Skip, func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&count, 1)
+		return nil, nil
+	})
+
+	h.Pause()
+	time.Sleep(30 * time.Millisecond)
+	paused := atomic.LoadInt32(&count)
+
+	h.Resume()
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&count) <= paused {
+		t.Fatal("expected more fires after Resume")
+	}
+
+	h.Cancel()
+	afterCancel := atomic.LoadInt32(&count)
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&count) != afterCancel {
+		t.Fatal("expected no more fires after Cancel")
+	}
+}
+
+// TestAtAcrossDSTBoundary exercises At's use of time.Date, which must resolve
+// wall-clock arithmetic across a DST transition rather than assuming a fixed
+// 24h day.
+func TestAtAcrossDSTBoundary(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	// 2023-03-12 02:00 America/New_York is the spring-forward transition.
+	from := time.Date(2023, 3, 11, 12, 0, 0, 0, loc)
+	clock := time.Date(0, 1, 1, 3, 0, 0, 0, loc)
+
+	trigger := At(clock)
+	next := trigger.next(from)
+
+	if next.Day() != 12 || next.Hour() != 3 {
+		t.Fatalf("expected 2023-03-12 03:00 local, got %v", next)
+	}
+}
+
+func TestPoolFullBackpressure(t *testing.T) {
+	block := make(chan struct{})
+	pool := workerpool.NewPool(context.Background(), workerpool.Option{NumberWorker: 1})
+	defer pool.Stop()
+	defer close(block)
+
+	// occupy the single worker and fill the (size-1) queue so TryDo fails.
+	pool.Execute(func(ctx context.Context) (interface{}, error) {
+		<-block
+		return nil, nil
+	})
+	pool.Execute(func(ctx context.Context) (interface{}, error) {
+		<-block
+		return nil, nil
+	})
+
+	s := New(pool)
+	defer s.Close()
+
+	var count int32
+	s.Every(5*time.Millisecond, Skip, func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&count, 1)
+		return nil, nil
+	})
+
+	time.Sleep(30 * time.Millisecond)
+	// the pool is saturated, so fires must be silently skipped rather than
+	// panicking or blocking the scheduler goroutine.
+	if atomic.LoadInt32(&count) != 0 {
+		t.Fatalf("expected 0 fires while pool is saturated, got %d", count)
+	}
+}
+
+func TestCronTrigger(t *testing.T) {
+	trigger, err := Cron("30 4 1 * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := trigger.next(from)
+	want := time.Date(2024, 1, 1, 4, 30, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
+
+func TestCronDayOfMonthOrDayOfWeek(t *testing.T) {
+	// When both day-of-month and day-of-week are restricted, standard cron
+	// ORs them: a day matches if either field matches. dom=20 falls on a
+	// Saturday in January 2024, but dow=3 (Wednesday) matches Jan 3 first.
+	trigger, err := Cron("0 0 20 * 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := trigger.next(from)
+	want := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
+
+func TestCronInvalidSpec(t *testing.T) {
+	if _, err := Cron("bogus"); err == nil {
+		t.Fatal("expected error for malformed cron spec")
+	}
+}
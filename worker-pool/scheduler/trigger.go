@@ -0,0 +1,66 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package scheduler
+
+import "time"
+
+// Trigger computes the next fire time strictly after from.
+type Trigger interface {
+	next(from time.Time) time.Time
+}
+
+// Every builds a Trigger that fires at a fixed interval. Scheduler computes
+// each next fire from the previous fire time (not from time.Now), so a slow
+// consumer does not cause the trigger to drift; if a fire time has already
+// passed, Scheduler resumes from the current time instead of "catching up" by
+// firing in a burst.
+func Every(d time.Duration) Trigger {
+	return everyTrigger{d: d}
+}
+
+type everyTrigger struct{ d time.Duration }
+
+func (t everyTrigger) next(from time.Time) time.Time {
+	return from.Add(t.d)
+}
+
+// At builds a Trigger that fires once per day at the wall-clock time carried
+// by clock (only its hour/minute/second/nanosecond and Location are used).
+// time.Date is used to compute each occurrence, so DST transitions are
+// resolved the same way the time package resolves any other wall-clock
+// arithmetic.
+func At(clock time.Time) Trigger {
+	return atTrigger{
+		hour: clock.Hour(),
+		min:  clock.Minute(),
+		sec:  clock.Second(),
+		nsec: clock.Nanosecond(),
+		loc:  clock.Location(),
+	}
+}
+
+type atTrigger struct {
+	hour, min, sec, nsec int
+	loc                  *time.Location
+}
+
+func (t atTrigger) next(from time.Time) time.Time {
+	from = from.In(t.loc)
+	next := time.Date(from.Year(), from.Month(), from.Day(), t.hour, t.min, t.sec, t.nsec, t.loc)
+	if !next.After(from) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a point-in-time snapshot of a Pool's activity.
+type Stats struct {
+	// QueueLength is the number of tasks currently queued, not yet started.
+	QueueLength int
+	// CoreWorkers is the number of core (non-expanded) workers alive.
+	CoreWorkers int32
+	// ExpandedWorkers is the number of on-demand workers alive.
+	ExpandedWorkers int32
+	// TasksAccepted is the total number of tasks ever accepted onto the pool.
+	TasksAccepted uint64
+	// TasksRejected is the total number of tasks ever rejected.
+	TasksRejected uint64
+	// TasksCompleted is the total number of tasks that finished executing.
+	TasksCompleted uint64
+	// AvgTaskLatencyMillis is an exponential moving average of task
+	// execution duration, in milliseconds.
+	AvgTaskLatencyMillis float64
+}
+
+// Stats returns a snapshot of the pool's current activity.
+func (p *Pool) Stats() Stats {
+	accepted, rejected, completed, avgLatencyMillis := p.stats.snapshot()
+
+	return Stats{
+		QueueLength:          p.scheduler.Len(),
+		CoreWorkers:          atomic.LoadInt32(&p.coreWorkers),
+		ExpandedWorkers:      atomic.LoadInt32(&p.expanded),
+		TasksAccepted:        accepted,
+		TasksRejected:        rejected,
+		TasksCompleted:       completed,
+		AvgTaskLatencyMillis: avgLatencyMillis,
+	}
+}
+
+// poolStats accumulates the counters and latency average backing Stats. It is
+// kept separate from Observer since Stats is always available, whether or
+// not an Observer is configured.
+type poolStats struct {
+	mu               sync.Mutex
+	accepted         uint64
+	rejected         uint64
+	completed        uint64
+	avgLatencyMillis float64
+}
+
+func (s *poolStats) recordAccept() {
+	s.mu.Lock()
+	s.accepted++
+	s.mu.Unlock()
+}
+
+func (s *poolStats) recordReject() {
+	s.mu.Lock()
+	s.rejected++
+	s.mu.Unlock()
+}
+
+// recordFinish folds dur into the moving average with a fixed decay, giving
+// recent tasks more weight without keeping a full latency history.
+func (s *poolStats) recordFinish(dur time.Duration) {
+	const decay = 0.2
+	ms := float64(dur) / float64(time.Millisecond)
+
+	s.mu.Lock()
+	s.completed++
+	if s.completed == 1 {
+		s.avgLatencyMillis = ms
+	} else {
+		s.avgLatencyMillis = decay*ms + (1-decay)*s.avgLatencyMillis
+	}
+	s.mu.Unlock()
+}
+
+func (s *poolStats) snapshot() (accepted, rejected, completed uint64, avgLatencyMillis float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted, s.rejected, s.completed, s.avgLatencyMillis
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WeightedFairScheduler round-robins across "task groups" (the group
+// attached via NewTaskWithGroup) so a burst of work from one caller cannot
+// starve another — useful when a single Pool is shared across tenants. Tasks
+// with no group are treated as belonging to the empty-string group. Like
+// PriorityScheduler, it is unbounded: Push never blocks, and for the same
+// reason Option.ExpandableLimit has no effect when this is the configured
+// Scheduler.
+type WeightedFairScheduler struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queues map[string][]*Task
+	order  []string
+	cursor int
+	closed bool
+}
+
+// NewWeightedFairScheduler creates an empty WeightedFairScheduler.
+func NewWeightedFairScheduler() *WeightedFairScheduler {
+	s := &WeightedFairScheduler{queues: make(map[string][]*Task)}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// TryPush enqueues t. It always succeeds unless the scheduler has been closed.
+func (s *WeightedFairScheduler) TryPush(t *Task) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	if _, ok := s.queues[t.group]; !ok {
+		s.order = append(s.order, t.group)
+	}
+	s.queues[t.group] = append(s.queues[t.group], t)
+	s.cond.Broadcast()
+	return true
+}
+
+// Push enqueues t. Since the scheduler is unbounded, it never actually
+// blocks; ctx and stop are accepted to satisfy Scheduler.
+func (s *WeightedFairScheduler) Push(ctx context.Context, stop <-chan struct{}, t *Task) bool {
+	return s.TryPush(t)
+}
+
+// Pop returns the next task from the next non-empty group in round-robin
+// order, blocking until one is available, timeout elapses, or the scheduler
+// is closed and drained.
+func (s *WeightedFairScheduler) Pop(timeout time.Duration) (t *Task, gotTask bool, closed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var timedOut int32
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			atomic.StoreInt32(&timedOut, 1)
+			s.mu.Lock()
+			s.cond.Broadcast()
+			s.mu.Unlock()
+		})
+		defer timer.Stop()
+	}
+
+	for !s.hasWorkLocked() && !s.closed {
+		if timeout > 0 && atomic.LoadInt32(&timedOut) == 1 {
+			return nil, false, false
+		}
+		s.cond.Wait()
+	}
+
+	for i := 0; i < len(s.order); i++ {
+		idx := (s.cursor + i) % len(s.order)
+		group := s.order[idx]
+		q := s.queues[group]
+		if len(q) == 0 {
+			continue
+		}
+
+		task := q[0]
+		q = q[1:]
+
+		if len(q) == 0 {
+			// the group's queue just drained: drop it from order/queues
+			// instead of leaving a dead entry behind forever, so a pool
+			// serving many rotating tenant/request-scoped groups doesn't
+			// grow these unboundedly. TryPush re-adds the group lazily the
+			// next time it sees work for it.
+			delete(s.queues, group)
+			s.order = append(s.order[:idx], s.order[idx+1:]...)
+			if len(s.order) == 0 {
+				s.cursor = 0
+			} else {
+				s.cursor = idx % len(s.order)
+			}
+		} else {
+			s.queues[group] = q
+			s.cursor = (idx + 1) % len(s.order)
+		}
+
+		return task, true, false
+	}
+	return nil, false, true
+}
+
+func (s *WeightedFairScheduler) hasWorkLocked() bool {
+	for _, q := range s.queues {
+		if len(q) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Close stops the scheduler, waking any worker blocked in Pop.
+func (s *WeightedFairScheduler) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// Len reports the number of tasks currently queued across all groups.
+func (s *WeightedFairScheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	for _, q := range s.queues {
+		total += len(q)
+	}
+	return total
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 LINE Corporation
+//
+// LINE Corporation licenses this file to you under the Apache License,
+// version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at:
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package workerpool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWeightedFairScheduler(t *testing.T) {
+	p := NewPool(context.Background(), Option{
+		NumberWorker:     1,
+		DisableAutoStart: true,
+		Scheduler:        NewWeightedFairScheduler(),
+	})
+
+	order := make(chan string, 4)
+	// tenant "a" bursts 3 tasks before tenant "b" submits 1; round-robin must
+	// still interleave them rather than starving "b" behind "a"'s backlog.
+	for i := 0; i < 3; i++ {
+		p.ExecuteWithGroup("a", func(ctx context.Context) (interface{}, error) { order <- "a"; return nil, nil })
+	}
+	p.ExecuteWithGroup("b", func(ctx context.Context) (interface{}, error) { order <- "b"; return nil, nil })
+
+	p.Start()
+	defer p.Stop()
+
+	want := []string{"a", "b", "a", "a"}
+	for _, w := range want {
+		select {
+		case got := <-order:
+			if got != w {
+				t.Fatalf("expected group %q next, got %q", w, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for task")
+		}
+	}
+}
+
+func TestWeightedFairSchedulerPrunesDrainedGroups(t *testing.T) {
+	s := NewWeightedFairScheduler()
+
+	// a long-lived scheduler serving many rotating tenant/request-scoped
+	// groups must not grow order/queues forever once a group's queue drains.
+	for i := 0; i < 100; i++ {
+		group := string(rune('a' + i%26))
+		s.TryPush(&Task{group: group})
+		if _, _, closed := s.Pop(0); closed {
+			t.Fatal("unexpected close while draining")
+		}
+	}
+
+	if got := len(s.order); got != 0 {
+		t.Fatalf("expected order to be pruned back to empty, got %d entries", got)
+	}
+	if got := len(s.queues); got != 0 {
+		t.Fatalf("expected queues to be pruned back to empty, got %d entries", got)
+	}
+}
+
+func TestWeightedFairScheduler_PopTimeout(t *testing.T) {
+	s := NewWeightedFairScheduler()
+	start := time.Now()
+	task, gotTask, closed := s.Pop(10 * time.Millisecond)
+	if task != nil || gotTask || closed {
+		t.Fatalf("expected timeout with no task, got %+v %v %v", task, gotTask, closed)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Fatal("Pop returned before the timeout elapsed")
+	}
+}